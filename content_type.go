@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// errUnsupportedMediaType is returned by sniffContentType when the bytes
+// on the wire don't match anything in the caller's allow-list, regardless
+// of what Content-Type the client claimed.
+var errUnsupportedMediaType = errors.New("unsupported media type")
+
+// allowedVideoTypes is the single source of truth for which video
+// Content-Types the API accepts, shared by the direct upload handler
+// (which sniffs it from the bytes) and the resumable upload handler
+// (which only has the client's declared type to go on until the parts
+// are assembled and probed).
+var allowedVideoTypes = []string{"video/mp4"}
+
+// sniffContentType reads the first 512 bytes of r and runs
+// http.DetectContentType on them instead of trusting the client-declared
+// Content-Type header, which a client can set to anything (e.g. naming a
+// .exe "image/png"). It returns the detected media type, a matching file
+// extension, and a reader that replays the sniffed bytes before the rest
+// of r so the caller can still copy the full file.
+func sniffContentType(r io.Reader, allowed []string) (mediaType, ext string, body io.Reader, err error) {
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(r, buf)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", "", nil, fmt.Errorf("reading file header: %w", err)
+	}
+	buf = buf[:n]
+	body = io.MultiReader(bytes.NewReader(buf), r)
+
+	detected := http.DetectContentType(buf)
+	mediaType, _, err = mime.ParseMediaType(detected)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("parsing detected media type: %w", err)
+	}
+
+	allowedType := false
+	for _, a := range allowed {
+		if mediaType == a {
+			allowedType = true
+			break
+		}
+	}
+	if !allowedType {
+		return "", "", nil, fmt.Errorf("%w: %s", errUnsupportedMediaType, mediaType)
+	}
+
+	ext, err = extensionForType(mediaType)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	return mediaType, ext, body, nil
+}
+
+// extensionForType picks a file extension for mediaType via
+// mime.ExtensionsByType, preferring the extension that matches the
+// subtype name (e.g. "jpeg" for "image/jpeg") since the registry can
+// return several equally valid options in an arbitrary order.
+func extensionForType(mediaType string) (string, error) {
+	exts, err := mime.ExtensionsByType(mediaType)
+	if err != nil {
+		return "", fmt.Errorf("looking up extension for %s: %w", mediaType, err)
+	}
+	if len(exts) == 0 {
+		return "", fmt.Errorf("no known extension for %s", mediaType)
+	}
+
+	_, subtype, _ := strings.Cut(mediaType, "/")
+	for _, e := range exts {
+		if strings.TrimPrefix(e, ".") == subtype {
+			return subtype, nil
+		}
+	}
+	return strings.TrimPrefix(exts[0], "."), nil
+}
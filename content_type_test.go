@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// pngFixture is the 8-byte PNG magic number followed by filler bytes,
+// enough for http.DetectContentType to recognize it as image/png
+// regardless of what name or Content-Type header it arrives with.
+var pngFixture = append([]byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}, bytes.Repeat([]byte{0}, 64)...)
+
+func TestSniffContentType_DetectsRealTypeRegardlessOfLabel(t *testing.T) {
+	mediaType, ext, body, err := sniffContentType(bytes.NewReader(pngFixture), []string{"image/png"})
+	if err != nil {
+		t.Fatalf("sniffContentType: %v", err)
+	}
+	if mediaType != "image/png" {
+		t.Errorf("mediaType = %q, want image/png", mediaType)
+	}
+	if ext != "png" {
+		t.Errorf("ext = %q, want png", ext)
+	}
+
+	replayed, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("reading body: %v", err)
+	}
+	if !bytes.Equal(replayed, pngFixture) {
+		t.Error("body reader didn't replay the full original bytes")
+	}
+}
+
+func TestSniffContentType_RejectsMislabeledFile(t *testing.T) {
+	// A file whose content is a PNG is mislabeled as mp4 by the client
+	// (e.g. it renamed it to "video.mp4"); sniffContentType must ignore
+	// the label and reject because the real bytes aren't in the allow-list.
+	_, _, _, err := sniffContentType(bytes.NewReader(pngFixture), []string{"video/mp4"})
+	if !errors.Is(err, errUnsupportedMediaType) {
+		t.Errorf("err = %v, want errUnsupportedMediaType", err)
+	}
+}
+
+func TestSniffContentType_RejectsExecutableMislabeledAsImage(t *testing.T) {
+	// "MZ" is the DOS/PE executable magic number. A malicious client
+	// could label this "image/jpeg" to slip it past a naive
+	// Content-Type-header check.
+	exeFixture := append([]byte{'M', 'Z'}, bytes.Repeat([]byte{0x90}, 64)...)
+
+	_, _, _, err := sniffContentType(bytes.NewReader(exeFixture), []string{"image/jpeg", "image/png"})
+	if !errors.Is(err, errUnsupportedMediaType) {
+		t.Errorf("err = %v, want errUnsupportedMediaType", err)
+	}
+}
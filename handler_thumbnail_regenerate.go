@@ -0,0 +1,77 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/auth"
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/media"
+	"github.com/google/uuid"
+)
+
+// handlerRegenerateThumbnail re-grabs a thumbnail from an already-uploaded
+// video at a caller-chosen timestamp, replacing whatever thumbnail the
+// video currently has. The download-and-ffmpeg work runs on media.Manager's
+// worker pool rather than inline in this handler, since the video being
+// downloaded can be close to 1GB. Registered as
+// POST /api/videos/{videoID}/thumbnails/regenerate?at=<seconds>.
+func (cfg *apiConfig) handlerRegenerateThumbnail(w http.ResponseWriter, r *http.Request) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+	userID, err := auth.ValidateJWT(token, cfg.jwtSecret)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	videoID, err := uuid.Parse(r.PathValue("videoID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid ID", err)
+		return
+	}
+
+	video, err := cfg.db.GetVideo(videoID)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "No video with videoID", err)
+		return
+	}
+	if video.UserID != userID {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized to update this video", nil)
+		return
+	}
+	if video.VideoURL == nil {
+		respondWithError(w, http.StatusBadRequest, "Video has no uploaded file to grab a frame from", nil)
+		return
+	}
+
+	var at *float64
+	if raw := r.URL.Query().Get("at"); raw != "" {
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid 'at' query param", err)
+			return
+		}
+		at = &parsed
+	}
+
+	job := media.Job{
+		ID:       uuid.New(),
+		VideoID:  videoID,
+		Kind:     media.JobKindThumbnailRegen,
+		VideoKey: *video.VideoURL,
+		At:       at,
+	}
+	if err := cfg.db.CreateVideoJob(job.ID, videoID); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Error recording thumbnail job", err)
+		return
+	}
+	cfg.media.Enqueue(job)
+
+	respondWithJSON(w, http.StatusAccepted, struct {
+		VideoID uuid.UUID `json:"video_id"`
+		JobID   uuid.UUID `json:"job_id"`
+	}{VideoID: videoID, JobID: job.ID})
+}
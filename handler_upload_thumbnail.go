@@ -6,12 +6,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
-	"io"
-	"mime"
 	"net/http"
-	"os"
-	"path/filepath"
-	"strings"
 
 	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/auth"
 	"github.com/google/uuid"
@@ -44,7 +39,7 @@ func (cfg *apiConfig) handlerUploadThumbnail(w http.ResponseWriter, r *http.Requ
 	r.ParseMultipartForm(maxMemory)
 
 	// "thumbnail" should match the HTML form input name
-	file, header, err := r.FormFile("thumbnail")
+	file, _, err := r.FormFile("thumbnail")
 	if err != nil {
 		respondWithError(w, http.StatusBadRequest, "Unable to parse form file", err)
 		return
@@ -68,46 +63,36 @@ func (cfg *apiConfig) handlerUploadThumbnail(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// check content type
-	contentType := header.Header.Get("Content-Type")
-	mediatype, _, err := mime.ParseMediaType(contentType)
+	// Sniff the real content type from the bytes themselves rather than
+	// trusting the client-declared header, which a client can set to
+	// anything (e.g. naming a .exe "image/png").
+	mediatype, fileExtension, body, err := sniffContentType(file, []string{"image/jpeg", "image/png"})
 	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Error parsing media type", err)
-		return
-	}
-
-	if !(mediatype == "image/jpeg" || mediatype == "image/png") {
-		respondWithError(w, http.StatusBadRequest, "Media type is not allowed", nil)
+		if errors.Is(err, errUnsupportedMediaType) {
+			respondWithError(w, http.StatusUnsupportedMediaType, "Media type is not allowed", err)
+			return
+		}
+		respondWithError(w, http.StatusBadRequest, "Error reading file", err)
 		return
 	}
 
-	// generate file path
-	fileExtension := strings.Split(contentType, "/")[1]
+	// generate file name
 	buffer := make([]byte, 32)
 	_, err = rand.Read(buffer)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Error creating random file name", err)
 	}
 	fileName := base64.RawURLEncoding.EncodeToString(buffer) + "." + fileExtension
-	filepath := filepath.Join(cfg.assetsRoot, fileName)
 
-	// create file on filesystem
-	osFile, err := os.Create(filepath)
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Error creating file", nil)
-		return
-	}
-
-	// copy data to os file
-	_, err = io.Copy(osFile, file)
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Error copying data to file", err)
+	// store the thumbnail through the configured FileStore (local disk or S3)
+	if err := cfg.fileStore.Put(r.Context(), fileName, mediatype, body); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Error storing thumbnail", err)
 		return
 	}
 
-	// update video metadata
-	thumbnail_url := fmt.Sprintf("http://localhost:%s/assets/%s", cfg.port, fileName)
-	video.ThumbnailURL = &thumbnail_url
+	// update video metadata: like VideoURL, ThumbnailURL holds the
+	// FileStore key, which dbVideoToSignedVideo turns into a URL on read
+	video.ThumbnailURL = &fileName
 
 	err = cfg.db.UpdateVideo(video)
 	if err != nil {
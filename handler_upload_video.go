@@ -1,25 +1,20 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"crypto/rand"
 	"database/sql"
 	"encoding/base64"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"mime"
 	"net/http"
 	"os"
-	"os/exec"
-	"strings"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/auth"
 	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/database"
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/media"
 	"github.com/google/uuid"
 )
 
@@ -67,28 +62,27 @@ func (cfg *apiConfig) handlerUploadVideo(w http.ResponseWriter, r *http.Request)
 	}
 
 	// "video" should match the HTML form input name
-	file, header, err := r.FormFile("video")
+	file, _, err := r.FormFile("video")
 	if err != nil {
 		respondWithError(w, http.StatusBadRequest, "Unable to parse form file", err)
 		return
 	}
 	defer file.Close()
 
-	// check content type
-	contentType := header.Header.Get("Content-Type")
-	mediatype, _, err := mime.ParseMediaType(contentType)
+	// Sniff the real content type from the bytes themselves rather than
+	// trusting the client-declared header, which a client can set to
+	// anything (e.g. naming a .exe "video/mp4").
+	mediatype, fileExtension, body, err := sniffContentType(file, allowedVideoTypes)
 	if err != nil {
-		respondWithError(w, http.StatusBadRequest, "Error parsing media type", err)
-		return
-	}
-
-	if !(mediatype == "video/mp4") {
-		respondWithError(w, http.StatusBadRequest, "Media type is not allowed", nil)
+		if errors.Is(err, errUnsupportedMediaType) {
+			respondWithError(w, http.StatusUnsupportedMediaType, "Media type is not allowed", err)
+			return
+		}
+		respondWithError(w, http.StatusBadRequest, "Error reading file", err)
 		return
 	}
 
 	// generate file name
-	fileExtension := strings.Split(contentType, "/")[1]
 	buffer := make([]byte, 32)
 	_, err = rand.Read(buffer)
 	if err != nil {
@@ -96,13 +90,16 @@ func (cfg *apiConfig) handlerUploadVideo(w http.ResponseWriter, r *http.Request)
 	}
 	fileName := base64.RawURLEncoding.EncodeToString(buffer) + "." + fileExtension
 
-	// Save uploaded file to disk
+	// Persist the raw upload to disk before handing it off to the async
+	// ingestion pipeline. A 1 GiB upload can take minutes to remux, probe,
+	// and push to S3, so the request shouldn't block on any of that —
+	// the client watches progress via GET /api/video_progress/{videoID}.
 	originalFile, err := os.CreateTemp("", "tubely-upload.mp4")
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Error creating file", nil)
 		return
 	}
-	_, err = io.Copy(originalFile, file)
+	_, err = io.Copy(originalFile, body)
 	if err != nil {
 		originalFile.Close()
 		os.Remove(originalFile.Name())
@@ -111,184 +108,56 @@ func (cfg *apiConfig) handlerUploadVideo(w http.ResponseWriter, r *http.Request)
 	}
 	originalFile.Close()
 
-	// Process video for fast start
-	processedPath, err := processVideoForFastStart(originalFile.Name())
-	if err != nil {
+	// The Content-Type sniff above only looks at the first 512 bytes;
+	// confirm the full container/codec really is mp4/h264 before handing
+	// the upload to the pipeline.
+	if err := media.ValidateMP4H264(originalFile.Name()); err != nil {
 		os.Remove(originalFile.Name())
-		respondWithError(w, http.StatusInternalServerError, "Error processing video for fast start", err)
-		return
-	}
-	defer os.Remove(originalFile.Name()) // delete original
-	defer os.Remove(processedPath)       // delete processed
-
-	// Open processed file for upload
-	// we do this to avoid sending 3 request at the start of playing a video
-	processedFile, err := os.Open(processedPath)
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Error opening processed video", err)
-		return
-	}
-	defer processedFile.Close()
-
-	// Determine video orientation
-	orientation, err := getVideoAspectRatio(processedPath)
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Error getting aspect ratio", err)
-		return
-	}
-	objKey := orientation + "/" + fileName
-
-	// Upload to S3
-	_, err = cfg.s3Client.PutObject(r.Context(), &s3.PutObjectInput{
-		Bucket:      &cfg.s3Bucket,
-		Key:         &objKey,
-		ContentType: &mediatype,
-		Body:        processedFile,
-	})
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Failed to upload video to S3", err)
+		respondWithError(w, http.StatusUnsupportedMediaType, "Video is not a valid mp4/h264 file", err)
 		return
 	}
 
-	// Update video metadata
-	// videoURL := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", cfg.s3Bucket, cfg.s3Region, objKey)
-	videoURL := fmt.Sprintf("%s,%s", cfg.s3Bucket, objKey)
-	video.VideoURL = &videoURL
-
-	err = cfg.db.UpdateVideo(video)
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Error updating video metadata", err)
-		return
+	job := media.Job{
+		ID:          uuid.New(),
+		VideoID:     videoID,
+		Kind:        media.JobKindIngest,
+		UploadPath:  originalFile.Name(),
+		ObjectKey:   fileName,
+		ContentType: mediatype,
 	}
-
-	// generate Presigned URL
-	signedVideo, err := cfg.dbVideoToSignedVideo(video)
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Error generating signed video URL when uploading", err)
+	if err := cfg.db.CreateVideoJob(job.ID, job.VideoID); err != nil {
+		os.Remove(originalFile.Name())
+		respondWithError(w, http.StatusInternalServerError, "Error recording ingestion job", err)
 		return
 	}
-	respondWithJSON(w, http.StatusOK, signedVideo)
-}
-
-type ffprobeOutput struct {
-	Streams []struct {
-		Width  int `json:"width"`
-		Height int `json:"height"`
-	} `json:"streams"`
-}
+	cfg.media.Enqueue(job)
 
-// approx checks if two floats are close enough
-func approx(a, b float64) bool {
-	const tolerance = 0.05
-	return (a > b-tolerance) && (a < b+tolerance)
+	respondWithJSON(w, http.StatusAccepted, struct {
+		VideoID uuid.UUID `json:"video_id"`
+		JobID   uuid.UUID `json:"job_id"`
+	}{VideoID: videoID, JobID: job.ID})
 }
 
-func getVideoAspectRatio(filePath string) (string, error) {
-	cmd := exec.Command("ffprobe",
-		"-v", "error",
-		"-print_format", "json",
-		"-show_streams", filePath)
-
-	var out bytes.Buffer
-	cmd.Stdout = &out
-
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("failed to run ffprobe: %w", err)
-	}
-
-	var parsed ffprobeOutput
-	if err := json.Unmarshal(out.Bytes(), &parsed); err != nil {
-		return "", fmt.Errorf("failed to parse ffprobe output: %w", err)
-	}
-
-	if len(parsed.Streams) == 0 {
-		return "", errors.New("no streams found in video")
-	}
-
-	width := parsed.Streams[0].Width
-	height := parsed.Streams[0].Height
-
-	if width == 0 || height == 0 {
-		return "", errors.New("invalid width or height")
-	}
-
-	ratio := float64(width) / float64(height)
-
-	switch {
-	case approx(ratio, 16.0/9.0):
-		return "landscape", nil
-	case approx(ratio, 9.0/16.0):
-		return "portrait", nil
-	default:
-		return "other", nil
-	}
-}
-
-func processVideoForFastStart(filePath string) (string, error) {
-	outputPath := filePath + ".processing"
-
-	cmd := exec.Command(
-		"ffmpeg",
-		"-i", filePath,
-		"-c", "copy",
-		"-movflags", "faststart",
-		"-f", "mp4",
-		outputPath,
-	)
-
-	// Optional: print output for debugging
-	cmd.Stdout = nil
-	cmd.Stderr = nil
-
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("failed to process video for fast start: %w", err)
-	}
-
-	return outputPath, nil
-}
-
-func generatePresignedURL(s3Client *s3.Client, bucket, key string, expireTime time.Duration) (string, error) {
-	// Create a presign client from the standard S3 client
-	presignClient := s3.NewPresignClient(s3Client)
-
-	// Prepare the input for the presigned GET request
-	input := &s3.GetObjectInput{
-		Bucket: &bucket,
-		Key:    &key,
-	}
-
-	// Generate the presigned URL
-	req, err := presignClient.PresignGetObject(context.TODO(), input,
-		s3.WithPresignExpires(expireTime),
-	)
-	if err != nil {
-		return "", fmt.Errorf("failed to generate presigned URL: %w", err)
+func (cfg *apiConfig) dbVideoToSignedVideo(ctx context.Context, video database.Video) (database.Video, error) {
+	if video.ThumbnailURL != nil {
+		thumbnailURL, err := cfg.fileStore.URL(ctx, *video.ThumbnailURL, 15*time.Minute)
+		if err != nil {
+			return video, fmt.Errorf("failed to generate thumbnail URL: %w", err)
+		}
+		video.ThumbnailURL = &thumbnailURL
 	}
 
-	return req.URL, nil
-}
-
-func (cfg *apiConfig) dbVideoToSignedVideo(video database.Video) (database.Video, error) {
 	if video.VideoURL == nil {
 		return video, nil
 	}
 
-	// Expecting VideoURL to be in the format "bucket,key"
-	parts := strings.Split(*video.VideoURL, ",")
-	if len(parts) != 2 {
-		return video, fmt.Errorf("invalid video URL format; expected 'bucket,key'")
-	}
-
-	bucket := parts[0]
-	key := parts[1]
-
-	// Generate a presigned URL
-	signedURL, err := generatePresignedURL(cfg.s3Client, bucket, key, 15*time.Minute)
+	// VideoURL holds the FileStore key; ask the configured backend (local
+	// disk or S3) to turn it back into a URL clients can fetch.
+	url, err := cfg.fileStore.URL(ctx, *video.VideoURL, 15*time.Minute)
 	if err != nil {
-		return video, fmt.Errorf("failed to generate presigned URL: %w", err)
+		return video, fmt.Errorf("failed to generate video URL: %w", err)
 	}
 
-	// Update video with the signed URL
-	video.VideoURL = &signedURL
+	video.VideoURL = &url
 	return video, nil
 }
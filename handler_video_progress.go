@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/auth"
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/media"
+	"github.com/google/uuid"
+)
+
+// handlerGetVideoProgress streams ingestion progress for a video as
+// Server-Sent Events until the job reaches a terminal stage. Registered
+// as GET /api/video_progress/{videoID}.
+func (cfg *apiConfig) handlerGetVideoProgress(w http.ResponseWriter, r *http.Request) {
+	videoIDString := r.PathValue("videoID")
+	videoID, err := uuid.Parse(videoIDString)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid ID", err)
+		return
+	}
+
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	if _, err := auth.ValidateJWT(token, cfg.jwtSecret); err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondWithError(w, http.StatusInternalServerError, "Streaming unsupported", nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	writeUpdate := func(update media.Progress) bool {
+		payload, err := json.Marshal(update)
+		if err != nil {
+			return false
+		}
+		fmt.Fprintf(w, "data: %s\n\n", payload)
+		flusher.Flush()
+		return true
+	}
+
+	// A client can connect well after a job finished (or fail to connect
+	// until its very first update), in which case the subscription channel
+	// alone would never receive anything. SubscribeWithLatest registers
+	// the channel and reads the persisted snapshot under the same lock,
+	// so there's no window where a job can finish and close out its
+	// subscriber list between the snapshot read and the subscription.
+	updates, latest, ok := cfg.media.SubscribeWithLatest(videoID)
+	if ok {
+		if !writeUpdate(latest) {
+			return
+		}
+		if latest.Stage == media.StageDone || latest.Stage == media.StageFailed {
+			return
+		}
+	}
+
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			if !writeUpdate(update) {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/auth"
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/filestore"
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/media"
+	"github.com/google/uuid"
+)
+
+// multipartFileStore is implemented by FileStore backends that support
+// chunked uploads. Both LocalFileStore and S3FileStore satisfy it.
+type multipartFileStore interface {
+	filestore.MultipartStore
+}
+
+// handlerStartVideoUpload starts a resumable upload for an existing video
+// and returns an upload ID the client uses for every subsequent part.
+// Registered as POST /api/video_uploads.
+func (cfg *apiConfig) handlerStartVideoUpload(w http.ResponseWriter, r *http.Request) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+	userID, err := auth.ValidateJWT(token, cfg.jwtSecret)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	type parameters struct {
+		VideoID     uuid.UUID `json:"video_id"`
+		ContentType string    `json:"content_type"`
+	}
+	var params parameters
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Error decoding request body", err)
+		return
+	}
+
+	// The client only declares a Content-Type here; there's no body yet
+	// to sniff. Reject anything outside the allow-list up front so an
+	// obviously wrong upload fails fast instead of burning a multipart
+	// upload's worth of parts before ValidateMP4H264 catches it at
+	// completion time.
+	allowed := false
+	for _, t := range allowedVideoTypes {
+		if params.ContentType == t {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		respondWithError(w, http.StatusUnsupportedMediaType, "Media type is not allowed", nil)
+		return
+	}
+
+	video, err := cfg.db.GetVideo(params.VideoID)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "No video with videoID", err)
+		return
+	}
+	if video.UserID != userID {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized to upload video", nil)
+		return
+	}
+
+	store, ok := cfg.fileStore.(multipartFileStore)
+	if !ok {
+		respondWithError(w, http.StatusNotImplemented, "Configured FileStore does not support multipart uploads", nil)
+		return
+	}
+
+	key := params.VideoID.String() + "/" + uuid.NewString() + ".mp4"
+	storeUploadID, err := store.CreateMultipartUpload(r.Context(), key, params.ContentType)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Error starting multipart upload", err)
+		return
+	}
+
+	uploadID := uuid.New()
+	if err := cfg.db.CreateVideoUpload(uploadID, params.VideoID, key, params.ContentType, storeUploadID); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Error recording upload", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, struct {
+		UploadID uuid.UUID `json:"upload_id"`
+	}{UploadID: uploadID})
+}
+
+// handlerUploadVideoPart streams one chunk of a resumable upload straight
+// to the FileStore. Registered as PUT /api/video_uploads/{uploadID}/parts/{n}.
+func (cfg *apiConfig) handlerUploadVideoPart(w http.ResponseWriter, r *http.Request) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+	userID, err := auth.ValidateJWT(token, cfg.jwtSecret)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	uploadID, err := uuid.Parse(r.PathValue("uploadID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid upload ID", err)
+		return
+	}
+	partNumber, err := strconv.Atoi(r.PathValue("partNumber"))
+	if err != nil || partNumber < 1 {
+		respondWithError(w, http.StatusBadRequest, "Invalid part number", err)
+		return
+	}
+
+	upload, err := cfg.db.GetVideoUpload(uploadID)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "No upload with uploadID", err)
+		return
+	}
+
+	video, err := cfg.db.GetVideo(upload.VideoID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Error loading video", err)
+		return
+	}
+	if video.UserID != userID {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized to upload video", nil)
+		return
+	}
+
+	store, ok := cfg.fileStore.(multipartFileStore)
+	if !ok {
+		respondWithError(w, http.StatusNotImplemented, "Configured FileStore does not support multipart uploads", nil)
+		return
+	}
+
+	etag, err := store.UploadPart(r.Context(), upload.Key, upload.StoreUploadID, int32(partNumber), r.Body)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Error uploading part", err)
+		return
+	}
+
+	if err := cfg.db.AddVideoUploadPart(uploadID, int32(partNumber), etag); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Error recording part", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, struct {
+		PartNumber int32  `json:"part_number"`
+		ETag       string `json:"etag"`
+	}{PartNumber: int32(partNumber), ETag: etag})
+}
+
+// handlerCompleteVideoUpload finalizes a resumable upload once every part
+// has been received. Registered as POST /api/video_uploads/{uploadID}/complete.
+func (cfg *apiConfig) handlerCompleteVideoUpload(w http.ResponseWriter, r *http.Request) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+	userID, err := auth.ValidateJWT(token, cfg.jwtSecret)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	uploadID, err := uuid.Parse(r.PathValue("uploadID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid upload ID", err)
+		return
+	}
+
+	upload, err := cfg.db.GetVideoUpload(uploadID)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "No upload with uploadID", err)
+		return
+	}
+
+	video, err := cfg.db.GetVideo(upload.VideoID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Error loading video", err)
+		return
+	}
+	if video.UserID != userID {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized to upload video", nil)
+		return
+	}
+
+	store, ok := cfg.fileStore.(multipartFileStore)
+	if !ok {
+		respondWithError(w, http.StatusNotImplemented, "Configured FileStore does not support multipart uploads", nil)
+		return
+	}
+
+	parts := make([]filestore.Part, len(upload.Parts))
+	for i, p := range upload.Parts {
+		parts[i] = filestore.Part{Number: p.Number, ETag: p.ETag}
+	}
+
+	if err := store.CompleteMultipartUpload(r.Context(), upload.Key, upload.StoreUploadID, parts); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Error completing upload", err)
+		return
+	}
+
+	// The assembled object has never been through fast-start remuxing,
+	// mp4/h264 validation, or thumbnail generation — the same pipeline
+	// every direct upload goes through. Download it back and hand it to
+	// media.Manager instead of marking the video ready directly.
+	assembled, err := cfg.fileStore.Get(r.Context(), upload.Key)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Error fetching assembled upload", err)
+		return
+	}
+	defer assembled.Close()
+
+	rawFile, err := os.CreateTemp("", "tubely-upload.mp4")
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Error creating file", err)
+		return
+	}
+	if _, err := io.Copy(rawFile, assembled); err != nil {
+		rawFile.Close()
+		os.Remove(rawFile.Name())
+		respondWithError(w, http.StatusInternalServerError, "Error reading assembled upload", err)
+		return
+	}
+	rawFile.Close()
+
+	// The pipeline below re-uploads the processed video under its own
+	// orientation-prefixed key, so the raw assembled object at upload.Key
+	// is no longer needed. Best-effort: leaving it behind costs storage,
+	// not correctness.
+	if err := cfg.fileStore.Delete(r.Context(), upload.Key); err != nil {
+		fmt.Printf("handler: deleting assembled multipart object %s: %v\n", upload.Key, err)
+	}
+
+	if err := media.ValidateMP4H264(rawFile.Name()); err != nil {
+		os.Remove(rawFile.Name())
+		// The assembled object at upload.Key is already gone (deleted
+		// above), so this upload can't be retried or aborted into a
+		// consistent state later. Clear the row now rather than leaving
+		// it pointing at a key that will never exist again.
+		if err := cfg.db.DeleteVideoUpload(uploadID); err != nil {
+			fmt.Printf("handler: clearing upload state for %s: %v\n", uploadID, err)
+		}
+		respondWithError(w, http.StatusUnsupportedMediaType, "Assembled upload is not a valid mp4/h264 file", err)
+		return
+	}
+
+	job := media.Job{
+		ID:          uuid.New(),
+		VideoID:     upload.VideoID,
+		Kind:        media.JobKindIngest,
+		UploadPath:  rawFile.Name(),
+		ObjectKey:   filepath.Base(upload.Key),
+		ContentType: upload.ContentType,
+	}
+	if err := cfg.db.CreateVideoJob(job.ID, job.VideoID); err != nil {
+		os.Remove(rawFile.Name())
+		respondWithError(w, http.StatusInternalServerError, "Error recording ingestion job", err)
+		return
+	}
+	cfg.media.Enqueue(job)
+
+	if err := cfg.db.DeleteVideoUpload(uploadID); err != nil {
+		fmt.Printf("handler: clearing upload state for %s: %v\n", uploadID, err)
+	}
+
+	respondWithJSON(w, http.StatusAccepted, struct {
+		VideoID uuid.UUID `json:"video_id"`
+		JobID   uuid.UUID `json:"job_id"`
+	}{VideoID: upload.VideoID, JobID: job.ID})
+}
+
+// handlerAbortVideoUpload cancels a resumable upload and discards any
+// parts received so far. Registered as DELETE /api/video_uploads/{uploadID}.
+func (cfg *apiConfig) handlerAbortVideoUpload(w http.ResponseWriter, r *http.Request) {
+	token, err := auth.GetBearerToken(r.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+	userID, err := auth.ValidateJWT(token, cfg.jwtSecret)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	uploadID, err := uuid.Parse(r.PathValue("uploadID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid upload ID", err)
+		return
+	}
+
+	upload, err := cfg.db.GetVideoUpload(uploadID)
+	if err != nil {
+		respondWithError(w, http.StatusNotFound, "No upload with uploadID", err)
+		return
+	}
+
+	video, err := cfg.db.GetVideo(upload.VideoID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Error loading video", err)
+		return
+	}
+	if video.UserID != userID {
+		respondWithError(w, http.StatusUnauthorized, "Unauthorized to upload video", nil)
+		return
+	}
+
+	if store, ok := cfg.fileStore.(multipartFileStore); ok {
+		if err := store.AbortMultipartUpload(r.Context(), upload.Key, upload.StoreUploadID); err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Error aborting upload", err)
+			return
+		}
+	}
+
+	if err := cfg.db.DeleteVideoUpload(uploadID); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Error clearing upload state", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
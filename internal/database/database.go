@@ -0,0 +1,83 @@
+// Package database is a small JSON-file-backed persistence layer for
+// videos and the background job state the ingestion pipeline records
+// against them. Lookups of a missing row return sql.ErrNoRows, matching
+// what a real SQL-backed store would return, so callers elsewhere in the
+// codebase can keep using errors.Is(err, sql.ErrNoRows) unchanged.
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+type dbStructure struct {
+	Videos       map[string]Video       `json:"videos"`
+	VideoJobs    map[string]VideoJob    `json:"video_jobs"`
+	VideoUploads map[string]VideoUpload `json:"video_uploads"`
+}
+
+// DB is a JSON-file-backed store, safe for concurrent use.
+type DB struct {
+	path string
+	mu   sync.RWMutex
+}
+
+// NewDB opens the database file at path, creating it with empty tables
+// if it doesn't exist yet.
+func NewDB(path string) (*DB, error) {
+	db := &DB{path: path}
+	if err := db.ensure(); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+func (db *DB) ensure() error {
+	if _, err := os.Stat(db.path); os.IsNotExist(err) {
+		return db.write(emptyStructure())
+	}
+	return nil
+}
+
+func emptyStructure() dbStructure {
+	return dbStructure{
+		Videos:       map[string]Video{},
+		VideoJobs:    map[string]VideoJob{},
+		VideoUploads: map[string]VideoUpload{},
+	}
+}
+
+func (db *DB) read() (dbStructure, error) {
+	data, err := os.ReadFile(db.path)
+	if err != nil {
+		return dbStructure{}, fmt.Errorf("reading database file: %w", err)
+	}
+
+	structure := emptyStructure()
+	if err := json.Unmarshal(data, &structure); err != nil {
+		return dbStructure{}, fmt.Errorf("parsing database file: %w", err)
+	}
+	if structure.Videos == nil {
+		structure.Videos = map[string]Video{}
+	}
+	if structure.VideoJobs == nil {
+		structure.VideoJobs = map[string]VideoJob{}
+	}
+	if structure.VideoUploads == nil {
+		structure.VideoUploads = map[string]VideoUpload{}
+	}
+	return structure, nil
+}
+
+func (db *DB) write(structure dbStructure) error {
+	data, err := json.Marshal(structure)
+	if err != nil {
+		return fmt.Errorf("encoding database file: %w", err)
+	}
+	if err := os.WriteFile(db.path, data, 0o644); err != nil {
+		return fmt.Errorf("writing database file: %w", err)
+	}
+	return nil
+}
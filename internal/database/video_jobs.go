@@ -0,0 +1,111 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VideoJob records the lifecycle of one background ingestion job, so a
+// client reconnecting to GET /api/video_progress/{videoID} can be told
+// where a job stands even if it misses every live pubsub update.
+type VideoJob struct {
+	ID        uuid.UUID `json:"id"`
+	VideoID   uuid.UUID `json:"video_id"`
+	Stage     string    `json:"stage"`
+	Percent   float64   `json:"percent"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CreateVideoJob records a new job row for videoID in its initial stage.
+func (db *DB) CreateVideoJob(jobID, videoID uuid.UUID) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	structure, err := db.read()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	structure.VideoJobs[jobID.String()] = VideoJob{
+		ID:        jobID,
+		VideoID:   videoID,
+		Stage:     "uploaded",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	return db.write(structure)
+}
+
+// UpdateVideoJobStage updates jobID's stage, percent, and error message.
+// stage is stored as a plain string so this package doesn't need to
+// import internal/media, which already imports internal/database for
+// the Video type.
+func (db *DB) UpdateVideoJobStage(jobID uuid.UUID, stage string, percent float64, errMsg string) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	structure, err := db.read()
+	if err != nil {
+		return err
+	}
+	job, ok := structure.VideoJobs[jobID.String()]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	job.Stage = stage
+	job.Percent = percent
+	job.Error = errMsg
+	job.UpdatedAt = time.Now()
+	structure.VideoJobs[jobID.String()] = job
+	return db.write(structure)
+}
+
+// GetVideoJob returns sql.ErrNoRows if no job exists with jobID.
+func (db *DB) GetVideoJob(jobID uuid.UUID) (VideoJob, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	structure, err := db.read()
+	if err != nil {
+		return VideoJob{}, err
+	}
+	job, ok := structure.VideoJobs[jobID.String()]
+	if !ok {
+		return VideoJob{}, sql.ErrNoRows
+	}
+	return job, nil
+}
+
+// GetLatestVideoJobForVideo returns the most recently updated job for
+// videoID, or sql.ErrNoRows if none has ever been recorded.
+func (db *DB) GetLatestVideoJobForVideo(videoID uuid.UUID) (VideoJob, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	structure, err := db.read()
+	if err != nil {
+		return VideoJob{}, err
+	}
+
+	var latest VideoJob
+	found := false
+	for _, job := range structure.VideoJobs {
+		if job.VideoID != videoID {
+			continue
+		}
+		if !found || job.UpdatedAt.After(latest.UpdatedAt) {
+			latest = job
+			found = true
+		}
+	}
+	if !found {
+		return VideoJob{}, sql.ErrNoRows
+	}
+	return latest, nil
+}
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VideoUploadPart is one received chunk of a resumable upload.
+type VideoUploadPart struct {
+	Number int32  `json:"number"`
+	ETag   string `json:"etag"`
+}
+
+// VideoUpload tracks an in-progress resumable multipart upload: the
+// backend's own upload ID plus every part ETag received so far, so a
+// client can resume after a dropped connection instead of restarting.
+type VideoUpload struct {
+	ID            uuid.UUID         `json:"id"`
+	VideoID       uuid.UUID         `json:"video_id"`
+	Key           string            `json:"key"`
+	ContentType   string            `json:"content_type"`
+	StoreUploadID string            `json:"store_upload_id"`
+	Parts         []VideoUploadPart `json:"parts"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+// CreateVideoUpload records a new in-progress upload.
+func (db *DB) CreateVideoUpload(uploadID, videoID uuid.UUID, key, contentType, storeUploadID string) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	structure, err := db.read()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	structure.VideoUploads[uploadID.String()] = VideoUpload{
+		ID:            uploadID,
+		VideoID:       videoID,
+		Key:           key,
+		ContentType:   contentType,
+		StoreUploadID: storeUploadID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	return db.write(structure)
+}
+
+// GetVideoUpload returns sql.ErrNoRows if no upload exists with uploadID.
+func (db *DB) GetVideoUpload(uploadID uuid.UUID) (VideoUpload, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	structure, err := db.read()
+	if err != nil {
+		return VideoUpload{}, err
+	}
+	upload, ok := structure.VideoUploads[uploadID.String()]
+	if !ok {
+		return VideoUpload{}, sql.ErrNoRows
+	}
+	return upload, nil
+}
+
+// AddVideoUploadPart records partNumber's ETag, replacing any ETag
+// already recorded for that part number. This keeps a retried part from
+// accumulating duplicate entries that would otherwise reach
+// CompleteMultipartUpload as two parts with the same number.
+func (db *DB) AddVideoUploadPart(uploadID uuid.UUID, partNumber int32, etag string) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	structure, err := db.read()
+	if err != nil {
+		return err
+	}
+	upload, ok := structure.VideoUploads[uploadID.String()]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	replaced := false
+	for i, p := range upload.Parts {
+		if p.Number == partNumber {
+			upload.Parts[i].ETag = etag
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		upload.Parts = append(upload.Parts, VideoUploadPart{Number: partNumber, ETag: etag})
+	}
+
+	upload.UpdatedAt = time.Now()
+	structure.VideoUploads[uploadID.String()] = upload
+	return db.write(structure)
+}
+
+// DeleteVideoUpload discards upload state. It is not an error to delete
+// an uploadID that doesn't exist.
+func (db *DB) DeleteVideoUpload(uploadID uuid.UUID) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	structure, err := db.read()
+	if err != nil {
+		return err
+	}
+	delete(structure.VideoUploads, uploadID.String())
+	return db.write(structure)
+}
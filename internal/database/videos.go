@@ -0,0 +1,57 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Video is a video's metadata row. VideoURL and ThumbnailURL hold
+// FileStore keys, not URLs themselves — callers sign them into fetchable
+// URLs via the configured FileStore.
+type Video struct {
+	ID           uuid.UUID `json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	UserID       uuid.UUID `json:"user_id"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	VideoURL     *string   `json:"video_url,omitempty"`
+	ThumbnailURL *string   `json:"thumbnail_url,omitempty"`
+}
+
+// GetVideo returns sql.ErrNoRows if no video exists with id.
+func (db *DB) GetVideo(id uuid.UUID) (Video, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	structure, err := db.read()
+	if err != nil {
+		return Video{}, err
+	}
+	video, ok := structure.Videos[id.String()]
+	if !ok {
+		return Video{}, sql.ErrNoRows
+	}
+	return video, nil
+}
+
+// UpdateVideo overwrites the stored row for video.ID, returning
+// sql.ErrNoRows if it doesn't exist.
+func (db *DB) UpdateVideo(video Video) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	structure, err := db.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := structure.Videos[video.ID.String()]; !ok {
+		return sql.ErrNoRows
+	}
+
+	video.UpdatedAt = time.Now()
+	structure.Videos[video.ID.String()] = video
+	return db.write(structure)
+}
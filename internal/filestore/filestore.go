@@ -0,0 +1,32 @@
+// Package filestore abstracts where uploaded media bytes live so the HTTP
+// handlers don't need to know whether they're writing to local disk or S3.
+package filestore
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// FileStore writes and reads back media objects identified by a key (e.g.
+// "landscape/abc123.mp4"). Implementations decide how that key maps onto
+// local paths, S3 objects, or any other backend.
+type FileStore interface {
+	// Put stores body under key, tagging it with contentType where the
+	// backend supports it (e.g. S3 object metadata).
+	Put(ctx context.Context, key, contentType string, body io.Reader) error
+
+	// Get opens the object stored under key for reading. The caller must
+	// close it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the object stored under key. It is not an error to
+	// delete a key that doesn't exist.
+	Delete(ctx context.Context, key string) error
+
+	// URL returns a URL clients can use to fetch the object at key. expiry
+	// is the lifetime the caller asks for; how long the link really stays
+	// valid is up to the backend (S3FileStore hands out cached links whose
+	// lifetime follows its presign cache's TTL, not expiry).
+	URL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
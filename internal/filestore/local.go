@@ -0,0 +1,59 @@
+package filestore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LocalFileStore persists objects as plain files under root and serves
+// them back through baseURL (e.g. "http://localhost:8091/assets"). It's
+// used for local development and CI so neither requires AWS credentials.
+type LocalFileStore struct {
+	root    string
+	baseURL string
+}
+
+func NewLocalFileStore(root, baseURL string) *LocalFileStore {
+	return &LocalFileStore{root: root, baseURL: baseURL}
+}
+
+func (s *LocalFileStore) Put(ctx context.Context, key, contentType string, body io.Reader) error {
+	dst := filepath.Join(s.root, key)
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return fmt.Errorf("creating asset directory for %s: %w", key, err)
+	}
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("creating asset file %s: %w", key, err)
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, body); err != nil {
+		return fmt.Errorf("writing asset file %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *LocalFileStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(s.root, key))
+	if err != nil {
+		return nil, fmt.Errorf("opening asset file %s: %w", key, err)
+	}
+	return f, nil
+}
+
+func (s *LocalFileStore) Delete(ctx context.Context, key string) error {
+	if err := os.Remove(filepath.Join(s.root, key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("deleting asset file %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *LocalFileStore) URL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return fmt.Sprintf("%s/%s", s.baseURL, key), nil
+}
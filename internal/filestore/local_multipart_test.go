@@ -0,0 +1,52 @@
+package filestore
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLocalMultipartUpload_OutOfOrderAndRetriedParts(t *testing.T) {
+	ctx := context.Background()
+	store := NewLocalFileStore(t.TempDir(), "http://localhost/assets")
+
+	uploadID, err := store.CreateMultipartUpload(ctx, "video.mp4", "video/mp4")
+	if err != nil {
+		t.Fatalf("CreateMultipartUpload: %v", err)
+	}
+
+	// Part 2 arrives before part 1.
+	if _, err := store.UploadPart(ctx, "video.mp4", uploadID, 2, strings.NewReader("world")); err != nil {
+		t.Fatalf("UploadPart(2): %v", err)
+	}
+	if _, err := store.UploadPart(ctx, "video.mp4", uploadID, 1, strings.NewReader("wrong-hello")); err != nil {
+		t.Fatalf("UploadPart(1) first attempt: %v", err)
+	}
+	// Part 1 is retried with the correct bytes; the retry must replace,
+	// not append to, the first attempt.
+	if _, err := store.UploadPart(ctx, "video.mp4", uploadID, 1, strings.NewReader("hello")); err != nil {
+		t.Fatalf("UploadPart(1) retry: %v", err)
+	}
+
+	if err := store.CompleteMultipartUpload(ctx, "video.mp4", uploadID, []Part{
+		{Number: 2, ETag: uploadID + "-2"},
+		{Number: 1, ETag: uploadID + "-1"},
+	}); err != nil {
+		t.Fatalf("CompleteMultipartUpload: %v", err)
+	}
+
+	r, err := store.Get(ctx, "video.mp4")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer r.Close()
+
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("reading assembled object: %v", err)
+	}
+	if want := "helloworld"; string(got) != want {
+		t.Errorf("assembled object = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,66 @@
+package filestore
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalFileStore_PutGetDelete(t *testing.T) {
+	ctx := context.Background()
+	store := NewLocalFileStore(t.TempDir(), "http://localhost:8091/assets")
+
+	if err := store.Put(ctx, "landscape/a.mp4", "video/mp4", strings.NewReader("hello")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	r, err := store.Get(ctx, "landscape/a.mp4")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	data, err := io.ReadAll(r)
+	r.Close()
+	if err != nil {
+		t.Fatalf("reading: %v", err)
+	}
+	if string(data) != "hello" {
+		t.Errorf("data = %q, want %q", data, "hello")
+	}
+
+	url, err := store.URL(ctx, "landscape/a.mp4", 0)
+	if err != nil {
+		t.Fatalf("URL: %v", err)
+	}
+	if url != "http://localhost:8091/assets/landscape/a.mp4" {
+		t.Errorf("URL = %q", url)
+	}
+
+	if err := store.Delete(ctx, "landscape/a.mp4"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, "landscape/a.mp4"); err == nil {
+		t.Error("Get after Delete succeeded, want error")
+	}
+}
+
+func TestLocalFileStore_DeleteMissingKeyIsNotAnError(t *testing.T) {
+	store := NewLocalFileStore(t.TempDir(), "http://localhost:8091/assets")
+	if err := store.Delete(context.Background(), "never-existed.mp4"); err != nil {
+		t.Errorf("Delete of missing key = %v, want nil", err)
+	}
+}
+
+func TestLocalFileStore_PutCreatesNestedDirectories(t *testing.T) {
+	root := t.TempDir()
+	store := NewLocalFileStore(root, "http://localhost:8091/assets")
+
+	if err := store.Put(context.Background(), "portrait/nested/b.mp4", "video/mp4", strings.NewReader("x")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "portrait/nested/b.mp4")); err != nil {
+		t.Errorf("stat written file: %v", err)
+	}
+}
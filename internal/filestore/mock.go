@@ -0,0 +1,49 @@
+package filestore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// MockFileStore is an in-memory FileStore for handler tests, so they can
+// exercise upload/delete/URL logic without touching disk or AWS.
+type MockFileStore struct {
+	Objects map[string][]byte
+	URLFunc func(key string) string
+}
+
+func NewMockFileStore() *MockFileStore {
+	return &MockFileStore{Objects: make(map[string][]byte)}
+}
+
+func (m *MockFileStore) Put(ctx context.Context, key, contentType string, body io.Reader) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	m.Objects[key] = data
+	return nil
+}
+
+func (m *MockFileStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	data, ok := m.Objects[key]
+	if !ok {
+		return nil, fmt.Errorf("no mock object for key %s", key)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *MockFileStore) Delete(ctx context.Context, key string) error {
+	delete(m.Objects, key)
+	return nil
+}
+
+func (m *MockFileStore) URL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if m.URLFunc != nil {
+		return m.URLFunc(key), nil
+	}
+	return "mock://" + key, nil
+}
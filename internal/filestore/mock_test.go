@@ -0,0 +1,69 @@
+package filestore
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestMockFileStore_PutGetDelete(t *testing.T) {
+	ctx := context.Background()
+	store := NewMockFileStore()
+
+	if err := store.Put(ctx, "videos/a.mp4", "video/mp4", strings.NewReader("hello")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	r, err := store.Get(ctx, "videos/a.mp4")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	data, err := io.ReadAll(r)
+	r.Close()
+	if err != nil {
+		t.Fatalf("reading: %v", err)
+	}
+	if string(data) != "hello" {
+		t.Errorf("data = %q, want %q", data, "hello")
+	}
+
+	if err := store.Delete(ctx, "videos/a.mp4"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, "videos/a.mp4"); err == nil {
+		t.Error("Get after Delete succeeded, want error")
+	}
+}
+
+func TestMockFileStore_GetMissingKey(t *testing.T) {
+	store := NewMockFileStore()
+	if _, err := store.Get(context.Background(), "nope"); err == nil {
+		t.Error("Get on missing key succeeded, want error")
+	}
+}
+
+func TestMockFileStore_URL(t *testing.T) {
+	store := NewMockFileStore()
+
+	url, err := store.URL(context.Background(), "videos/a.mp4", 0)
+	if err != nil {
+		t.Fatalf("URL: %v", err)
+	}
+	if url != "mock://videos/a.mp4" {
+		t.Errorf("URL = %q, want default mock:// scheme", url)
+	}
+
+	store.URLFunc = func(key string) string { return "https://cdn.example.com/" + key }
+	url, err = store.URL(context.Background(), "videos/a.mp4", 0)
+	if err != nil {
+		t.Fatalf("URL: %v", err)
+	}
+	if url != "https://cdn.example.com/videos/a.mp4" {
+		t.Errorf("URL = %q, want URLFunc override applied", url)
+	}
+}
+
+func TestMockFileStore_SatisfiesFileStore(t *testing.T) {
+	var _ FileStore = NewMockFileStore()
+}
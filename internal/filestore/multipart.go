@@ -0,0 +1,23 @@
+package filestore
+
+import (
+	"context"
+	"io"
+)
+
+// Part identifies one uploaded chunk of a multipart upload, and the
+// backend-assigned ETag needed to complete it.
+type Part struct {
+	Number int32
+	ETag   string
+}
+
+// MultipartStore is implemented by FileStore backends that can accept an
+// upload as a series of independently-retriable chunks instead of one
+// long-lived stream, so a flaky connection only costs the current chunk.
+type MultipartStore interface {
+	CreateMultipartUpload(ctx context.Context, key, contentType string) (uploadID string, err error)
+	UploadPart(ctx context.Context, key, uploadID string, partNumber int32, body io.Reader) (etag string, err error)
+	CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []Part) error
+	AbortMultipartUpload(ctx context.Context, key, uploadID string) error
+}
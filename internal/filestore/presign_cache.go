@@ -0,0 +1,115 @@
+package filestore
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// PresignCacheMetrics tracks how a presignCache served its requests, for
+// exposing as process metrics.
+type PresignCacheMetrics struct {
+	Hits          int64
+	Misses        int64
+	Regenerations int64
+}
+
+type cachedURL struct {
+	url       string
+	expiresAt time.Time
+}
+
+// presignCache memoizes signed URLs keyed by "bucket|key" so repeated
+// reads of the same object (e.g. a video listing endpoint returning the
+// same video to many clients) return the same URL instead of a fresh
+// signature every time, which also lets CDN/browser caches actually hit.
+// Entries are served as-is until they're within refreshWindow of
+// expiring, at which point a background goroutine regenerates them
+// rather than blocking the caller that noticed.
+type presignCache struct {
+	sign          func(ctx context.Context, key string, ttl time.Duration) (string, error)
+	ttl           time.Duration
+	refreshWindow time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cachedURL
+	pending map[string]bool
+	metrics PresignCacheMetrics
+}
+
+func newPresignCache(ttl, refreshWindow time.Duration, sign func(ctx context.Context, key string, ttl time.Duration) (string, error)) *presignCache {
+	return &presignCache{
+		sign:          sign,
+		ttl:           ttl,
+		refreshWindow: refreshWindow,
+		entries:       make(map[string]cachedURL),
+		pending:       make(map[string]bool),
+	}
+}
+
+func (c *presignCache) get(ctx context.Context, bucket, key string) (string, error) {
+	cacheKey := bucket + "|" + key
+
+	c.mu.Lock()
+	entry, ok := c.entries[cacheKey]
+	if !ok {
+		c.metrics.Misses++
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		return c.regenerate(ctx, cacheKey, key)
+	}
+
+	c.mu.Lock()
+	c.metrics.Hits++
+	needsRefresh := time.Until(entry.expiresAt) < c.refreshWindow && !c.pending[cacheKey]
+	if needsRefresh {
+		c.pending[cacheKey] = true
+	}
+	c.mu.Unlock()
+
+	if needsRefresh {
+		go c.refreshAhead(cacheKey, key)
+	}
+
+	return entry.url, nil
+}
+
+func (c *presignCache) regenerate(ctx context.Context, cacheKey, key string) (string, error) {
+	url, err := c.sign(ctx, key, c.ttl)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.entries[cacheKey] = cachedURL{url: url, expiresAt: time.Now().Add(c.ttl)}
+	c.metrics.Regenerations++
+	c.mu.Unlock()
+
+	return url, nil
+}
+
+// refreshAhead regenerates a soon-to-expire entry in the background, off
+// the request that noticed it needed refreshing.
+func (c *presignCache) refreshAhead(cacheKey, key string) {
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, cacheKey)
+		c.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := c.regenerate(ctx, cacheKey, key); err != nil {
+		fmt.Printf("filestore: refreshing presigned url for %s: %v\n", key, err)
+	}
+}
+
+func (c *presignCache) Metrics() PresignCacheMetrics {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.metrics
+}
@@ -0,0 +1,90 @@
+package filestore
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func countingSigner(calls *int64) func(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return func(ctx context.Context, key string, ttl time.Duration) (string, error) {
+		n := atomic.AddInt64(calls, 1)
+		return fmt.Sprintf("https://example.com/%s?sig=%d", key, n), nil
+	}
+}
+
+func TestPresignCache_HitsAndMisses(t *testing.T) {
+	var calls int64
+	cache := newPresignCache(time.Minute, time.Second, countingSigner(&calls))
+	ctx := context.Background()
+
+	first, err := cache.get(ctx, "bucket", "video.mp4")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	second, err := cache.get(ctx, "bucket", "video.mp4")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	if first != second {
+		t.Errorf("got two different URLs from a warm cache: %q vs %q", first, second)
+	}
+	if atomic.LoadInt64(&calls) != 1 {
+		t.Errorf("sign called %d times, want 1", calls)
+	}
+
+	metrics := cache.Metrics()
+	if metrics.Misses != 1 || metrics.Hits != 1 || metrics.Regenerations != 1 {
+		t.Errorf("metrics = %+v, want 1 miss, 1 hit, 1 regeneration", metrics)
+	}
+}
+
+func TestPresignCache_RefreshesAheadOfExpiry(t *testing.T) {
+	var calls int64
+	// A refreshWindow wider than the ttl means every get() is already
+	// within the refresh window, so the very next get should trigger a
+	// background regeneration.
+	cache := newPresignCache(10*time.Millisecond, time.Hour, countingSigner(&calls))
+	ctx := context.Background()
+
+	if _, err := cache.get(ctx, "bucket", "video.mp4"); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if _, err := cache.get(ctx, "bucket", "video.mp4"); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt64(&calls) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	if got := atomic.LoadInt64(&calls); got < 2 {
+		t.Errorf("sign called %d times, want at least 2 (one regeneration ahead of expiry)", got)
+	}
+}
+
+// BenchmarkPresignCache_Get demonstrates the reduction in signing work a
+// warm cache buys: after the first get() signs the key once, every
+// subsequent get() for the same key is served from the cache instead of
+// invoking sign again.
+func BenchmarkPresignCache_Get(b *testing.B) {
+	var calls int64
+	cache := newPresignCache(time.Hour, time.Minute, countingSigner(&calls))
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := cache.get(ctx, "bucket", "video.mp4"); err != nil {
+			b.Fatalf("get: %v", err)
+		}
+	}
+	b.StopTimer()
+
+	b.ReportMetric(float64(atomic.LoadInt64(&calls)), "signs")
+	metrics := cache.Metrics()
+	b.Logf("%d gets produced %d signs (metrics: %+v)", b.N, calls, metrics)
+}
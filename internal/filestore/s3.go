@@ -0,0 +1,91 @@
+package filestore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3FileStore stores objects in an S3 bucket and vends time-limited
+// presigned GET URLs for reading them back. Presigned URLs are memoized
+// by a presignCache so a listing endpoint returning N videos doesn't
+// recompute N signatures per request.
+type S3FileStore struct {
+	client *s3.Client
+	bucket string
+	region string
+	cache  *presignCache
+}
+
+// NewS3FileStore builds an S3FileStore whose presigned URLs are valid for
+// presignTTL and get refreshed in the background once they're within
+// refreshWindow of expiring.
+func NewS3FileStore(client *s3.Client, bucket, region string, presignTTL, refreshWindow time.Duration) *S3FileStore {
+	s := &S3FileStore{client: client, bucket: bucket, region: region}
+	s.cache = newPresignCache(presignTTL, refreshWindow, s.presign)
+	return s
+}
+
+// PresignMetrics reports this store's presigned URL cache hit/miss/
+// regeneration counters.
+func (s *S3FileStore) PresignMetrics() PresignCacheMetrics {
+	return s.cache.Metrics()
+}
+
+func (s *S3FileStore) Put(ctx context.Context, key, contentType string, body io.Reader) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      &s.bucket,
+		Key:         &key,
+		ContentType: &contentType,
+		Body:        body,
+	})
+	if err != nil {
+		return fmt.Errorf("uploading %s to s3: %w", key, err)
+	}
+	return nil
+}
+
+func (s *S3FileStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: &s.bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s from s3: %w", key, err)
+	}
+	return out.Body, nil
+}
+
+func (s *S3FileStore) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: &s.bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		return fmt.Errorf("deleting %s from s3: %w", key, err)
+	}
+	return nil
+}
+
+// URL returns a cached presigned GET URL for key, signing a fresh one on
+// a cache miss. The requested expiry is advisory: the cache's configured
+// TTL governs how long a signature is actually valid for.
+func (s *S3FileStore) URL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return s.cache.get(ctx, s.bucket, key)
+}
+
+func (s *S3FileStore) presign(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	presignClient := s3.NewPresignClient(s.client)
+
+	req, err := presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: &s.bucket,
+		Key:    &key,
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("generating presigned url for %s: %w", key, err)
+	}
+	return req.URL, nil
+}
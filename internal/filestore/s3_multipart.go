@@ -0,0 +1,74 @@
+package filestore
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// CreateMultipartUpload starts an S3 multipart upload for key and returns
+// the upload ID callers must pass to UploadPart/CompleteMultipartUpload.
+func (s *S3FileStore) CreateMultipartUpload(ctx context.Context, key, contentType string) (string, error) {
+	out, err := s.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      &s.bucket,
+		Key:         &key,
+		ContentType: &contentType,
+	})
+	if err != nil {
+		return "", fmt.Errorf("starting multipart upload for %s: %w", key, err)
+	}
+	return *out.UploadId, nil
+}
+
+func (s *S3FileStore) UploadPart(ctx context.Context, key, uploadID string, partNumber int32, body io.Reader) (string, error) {
+	out, err := s.client.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:     &s.bucket,
+		Key:        &key,
+		UploadId:   &uploadID,
+		PartNumber: &partNumber,
+		Body:       body,
+	})
+	if err != nil {
+		return "", fmt.Errorf("uploading part %d of %s: %w", partNumber, key, err)
+	}
+	return *out.ETag, nil
+}
+
+func (s *S3FileStore) CompleteMultipartUpload(ctx context.Context, key, uploadID string, parts []Part) error {
+	completedParts := make([]types.CompletedPart, len(parts))
+	for i, p := range parts {
+		completedParts[i] = types.CompletedPart{
+			PartNumber: aws.Int32(p.Number),
+			ETag:       aws.String(p.ETag),
+		}
+	}
+
+	_, err := s.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:   &s.bucket,
+		Key:      &key,
+		UploadId: &uploadID,
+		MultipartUpload: &types.CompletedMultipartUpload{
+			Parts: completedParts,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("completing multipart upload for %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *S3FileStore) AbortMultipartUpload(ctx context.Context, key, uploadID string) error {
+	_, err := s.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   &s.bucket,
+		Key:      &key,
+		UploadId: &uploadID,
+	})
+	if err != nil {
+		return fmt.Errorf("aborting multipart upload for %s: %w", key, err)
+	}
+	return nil
+}
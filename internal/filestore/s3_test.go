@@ -0,0 +1,12 @@
+package filestore
+
+import "testing"
+
+func TestS3FileStore_PresignMetrics(t *testing.T) {
+	store := NewS3FileStore(nil, "bucket", "us-east-1", 0, 0)
+
+	metrics := store.PresignMetrics()
+	if metrics != (PresignCacheMetrics{}) {
+		t.Errorf("metrics on a fresh store = %+v, want zero value", metrics)
+	}
+}
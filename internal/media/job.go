@@ -0,0 +1,54 @@
+// Package media runs video ingestion (fast-start remux, aspect-ratio
+// probing, upload) on background worker goroutines and streams per-stage
+// progress to any number of subscribers per video.
+package media
+
+import "github.com/google/uuid"
+
+// Stage identifies where an ingestion job currently is in the pipeline.
+type Stage string
+
+const (
+	StageUploaded   Stage = "uploaded"
+	StageProcessing Stage = "processing"
+	StageProbing    Stage = "probing"
+	StageS3Upload   Stage = "s3_upload"
+	StageDone       Stage = "done"
+	StageFailed     Stage = "failed"
+)
+
+// Progress is one update in a job's lifecycle, published to subscribers
+// as the job moves through stages.
+type Progress struct {
+	Stage   Stage   `json:"stage"`
+	Percent float64 `json:"percent"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// JobKind distinguishes what a Job asks a worker to do. Every caller must
+// set it explicitly; worker() treats an unrecognized (including zero)
+// value as an error rather than assuming ingestion.
+type JobKind string
+
+const (
+	JobKindIngest         JobKind = "ingest"
+	JobKindThumbnailRegen JobKind = "thumbnail_regen"
+)
+
+// Job is a single task queued for a worker goroutine. Which fields are
+// read depends on Kind: JobKindIngest uses UploadPath/ObjectKey/
+// ContentType, JobKindThumbnailRegen uses VideoKey/At.
+type Job struct {
+	ID      uuid.UUID
+	VideoID uuid.UUID
+	Kind    JobKind
+
+	// JobKindIngest fields.
+	UploadPath  string // temp file holding the raw upload on disk
+	ObjectKey   string // FileStore key the processed video is stored under
+	ContentType string
+
+	// JobKindThumbnailRegen fields.
+	VideoKey string   // FileStore key of the already-uploaded video
+	At       *float64 // timestamp in seconds to grab; nil means 10% in
+}
@@ -0,0 +1,311 @@
+package media
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/database"
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/filestore"
+	"github.com/google/uuid"
+)
+
+// Store is the subset of the database layer the ingestion pipeline needs:
+// reading and updating video metadata, and recording job state in the
+// video_jobs table so a client that reconnects mid-upload can resume
+// watching progress instead of starting over. Stage is passed as a plain
+// string rather than the Stage type so this package's Store interface
+// doesn't force internal/database to import internal/media back.
+type Store interface {
+	GetVideo(id uuid.UUID) (database.Video, error)
+	UpdateVideo(video database.Video) error
+
+	CreateVideoJob(jobID, videoID uuid.UUID) error
+	UpdateVideoJobStage(jobID uuid.UUID, stage string, percent float64, errMsg string) error
+	GetLatestVideoJobForVideo(videoID uuid.UUID) (database.VideoJob, error)
+}
+
+// Manager runs video ingestion jobs on a pool of worker goroutines and
+// fans out their progress to any number of SSE subscribers per video.
+type Manager struct {
+	store     Store
+	fileStore filestore.FileStore
+
+	jobs chan Job
+
+	mu   sync.Mutex
+	subs map[uuid.UUID][]chan Progress
+}
+
+// NewManager starts workers background goroutines that pull jobs off the
+// queue and run them to completion.
+func NewManager(store Store, fileStore filestore.FileStore, workers int) *Manager {
+	m := &Manager{
+		store:     store,
+		fileStore: fileStore,
+		jobs:      make(chan Job, 64),
+		subs:      make(map[uuid.UUID][]chan Progress),
+	}
+	for i := 0; i < workers; i++ {
+		go m.worker()
+	}
+	return m
+}
+
+// Enqueue schedules job to be picked up by the next free worker.
+func (m *Manager) Enqueue(job Job) {
+	m.jobs <- job
+}
+
+// SubscribeWithLatest atomically registers a subscription for videoID and
+// reports the most recently persisted progress for it (read from the
+// video_jobs row), all under the same lock.
+//
+// A separate "read latest, then subscribe" pair of calls would race
+// against closeSubs: if a job reaches StageDone/StageFailed (deleting
+// m.subs[videoID]) in the gap between the two calls, a subsequent
+// Subscribe recreates an entry nothing will ever publish to or close
+// again, hanging the caller forever. Because the persisted job row is
+// always written before the lock-protected fan-out/closeSubs step that
+// can delete a video's subscriber list (see publish/closeSubs below),
+// taking m.mu across both the registration and the store read here
+// guarantees one of two outcomes: either this call observes the
+// terminal state already (and the caller doesn't need the channel), or
+// it registers before closeSubs runs and that channel gets closed like
+// any other live subscriber's.
+func (m *Manager) SubscribeWithLatest(videoID uuid.UUID) (<-chan Progress, Progress, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ch := make(chan Progress, 16)
+	m.subs[videoID] = append(m.subs[videoID], ch)
+
+	job, err := m.store.GetLatestVideoJobForVideo(videoID)
+	if err != nil {
+		return ch, Progress{}, false
+	}
+	return ch, Progress{Stage: Stage(job.Stage), Percent: job.Percent, Error: job.Error}, true
+}
+
+func (m *Manager) worker() {
+	for job := range m.jobs {
+		switch job.Kind {
+		case JobKindIngest:
+			m.run(job)
+		case JobKindThumbnailRegen:
+			m.runThumbnailRegen(job)
+		default:
+			// A Job with an unset or unrecognized Kind is a caller bug:
+			// every enqueue site must set Kind explicitly. Fail the job
+			// loudly instead of silently treating it as ingestion.
+			m.fail(job, fmt.Errorf("unknown job kind %q", job.Kind))
+		}
+	}
+}
+
+func (m *Manager) run(job Job) {
+	defer os.Remove(job.UploadPath)
+
+	m.publish(job, Progress{Stage: StageUploaded, Percent: 0})
+
+	m.publish(job, Progress{Stage: StageProcessing, Percent: 10})
+	processedPath, err := processVideoForFastStart(job.UploadPath)
+	if err != nil {
+		m.fail(job, fmt.Errorf("processing for fast start: %w", err))
+		return
+	}
+	defer os.Remove(processedPath)
+
+	m.publish(job, Progress{Stage: StageProbing, Percent: 40})
+	orientation, err := getVideoAspectRatio(processedPath)
+	if err != nil {
+		m.fail(job, fmt.Errorf("getting aspect ratio: %w", err))
+		return
+	}
+
+	// Auto-generate a thumbnail at 10% into the video. This is best-effort:
+	// a user can always grab a different frame later via the regenerate
+	// endpoint, so a failure here shouldn't fail the whole upload.
+	thumbnailKey, err := m.generateAndUploadThumbnail(job, processedPath, 0.1)
+	if err != nil {
+		fmt.Printf("media: generating thumbnail for video %s: %v\n", job.VideoID, err)
+	}
+
+	processedFile, err := os.Open(processedPath)
+	if err != nil {
+		m.fail(job, fmt.Errorf("opening processed video: %w", err))
+		return
+	}
+	defer processedFile.Close()
+
+	info, err := processedFile.Stat()
+	if err != nil {
+		m.fail(job, fmt.Errorf("statting processed video: %w", err))
+		return
+	}
+
+	objKey := orientation + "/" + job.ObjectKey
+	reader := newProgressReader(processedFile, info.Size(), func(percent float64) {
+		m.publish(job, Progress{Stage: StageS3Upload, Percent: 50 + percent*0.5})
+	})
+	m.publish(job, Progress{Stage: StageS3Upload, Percent: 50})
+	if err := m.fileStore.Put(context.Background(), objKey, job.ContentType, reader); err != nil {
+		m.fail(job, fmt.Errorf("uploading video: %w", err))
+		return
+	}
+
+	video, err := m.store.GetVideo(job.VideoID)
+	if err != nil {
+		m.fail(job, fmt.Errorf("reloading video: %w", err))
+		return
+	}
+	video.VideoURL = &objKey
+	if thumbnailKey != "" && video.ThumbnailURL == nil {
+		video.ThumbnailURL = &thumbnailKey
+	}
+	if err := m.store.UpdateVideo(video); err != nil {
+		m.fail(job, fmt.Errorf("saving video metadata: %w", err))
+		return
+	}
+
+	m.publish(job, Progress{Stage: StageDone, Percent: 100})
+	m.closeSubs(job.VideoID)
+}
+
+// generateAndUploadThumbnail grabs a frame at fraction of the video's
+// duration, uploads it through the same FileStore as the video, and
+// returns the key it was stored under.
+func (m *Manager) generateAndUploadThumbnail(job Job, videoPath string, fraction float64) (string, error) {
+	duration, err := GetVideoDuration(videoPath)
+	if err != nil {
+		return "", fmt.Errorf("getting video duration: %w", err)
+	}
+
+	thumbnailPath, err := GenerateThumbnailAt(videoPath, duration*fraction)
+	if err != nil {
+		return "", fmt.Errorf("generating thumbnail: %w", err)
+	}
+	defer os.Remove(thumbnailPath)
+
+	f, err := os.Open(thumbnailPath)
+	if err != nil {
+		return "", fmt.Errorf("opening thumbnail: %w", err)
+	}
+	defer f.Close()
+
+	key := "thumbnails/" + job.VideoID.String() + ".jpg"
+	if err := m.fileStore.Put(context.Background(), key, "image/jpeg", f); err != nil {
+		return "", fmt.Errorf("uploading thumbnail: %w", err)
+	}
+	return key, nil
+}
+
+// runThumbnailRegen downloads job.VideoKey's stored video, grabs a frame
+// at job.At (or 10% in if nil), and replaces the video's thumbnail. It
+// runs on the same worker pool as ingestion jobs so a regenerate request
+// against a ~1GB video doesn't block the HTTP request that triggered it.
+func (m *Manager) runThumbnailRegen(job Job) {
+	m.publish(job, Progress{Stage: StageProcessing, Percent: 10})
+
+	src, err := m.fileStore.Get(context.Background(), job.VideoKey)
+	if err != nil {
+		m.fail(job, fmt.Errorf("fetching video: %w", err))
+		return
+	}
+	defer src.Close()
+
+	tmpFile, err := os.CreateTemp("", "tubely-regen-*.mp4")
+	if err != nil {
+		m.fail(job, fmt.Errorf("creating temp file: %w", err))
+		return
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := io.Copy(tmpFile, src); err != nil {
+		tmpFile.Close()
+		m.fail(job, fmt.Errorf("downloading video: %w", err))
+		return
+	}
+	tmpFile.Close()
+
+	m.publish(job, Progress{Stage: StageProbing, Percent: 40})
+	at := job.At
+	if at == nil {
+		duration, err := GetVideoDuration(tmpFile.Name())
+		if err != nil {
+			m.fail(job, fmt.Errorf("getting video duration: %w", err))
+			return
+		}
+		defaultAt := duration * 0.1
+		at = &defaultAt
+	}
+
+	thumbnailPath, err := GenerateThumbnailAt(tmpFile.Name(), *at)
+	if err != nil {
+		m.fail(job, fmt.Errorf("generating thumbnail: %w", err))
+		return
+	}
+	defer os.Remove(thumbnailPath)
+
+	thumbnailFile, err := os.Open(thumbnailPath)
+	if err != nil {
+		m.fail(job, fmt.Errorf("opening generated thumbnail: %w", err))
+		return
+	}
+	defer thumbnailFile.Close()
+
+	m.publish(job, Progress{Stage: StageS3Upload, Percent: 80})
+	key := "thumbnails/" + job.VideoID.String() + ".jpg"
+	if err := m.fileStore.Put(context.Background(), key, "image/jpeg", thumbnailFile); err != nil {
+		m.fail(job, fmt.Errorf("uploading thumbnail: %w", err))
+		return
+	}
+
+	video, err := m.store.GetVideo(job.VideoID)
+	if err != nil {
+		m.fail(job, fmt.Errorf("reloading video: %w", err))
+		return
+	}
+	video.ThumbnailURL = &key
+	if err := m.store.UpdateVideo(video); err != nil {
+		m.fail(job, fmt.Errorf("saving video metadata: %w", err))
+		return
+	}
+
+	m.publish(job, Progress{Stage: StageDone, Percent: 100})
+	m.closeSubs(job.VideoID)
+}
+
+func (m *Manager) fail(job Job, err error) {
+	m.publish(job, Progress{Stage: StageFailed, Error: err.Error()})
+	m.closeSubs(job.VideoID)
+}
+
+func (m *Manager) publish(job Job, p Progress) {
+	if err := m.store.UpdateVideoJobStage(job.ID, string(p.Stage), p.Percent, p.Error); err != nil {
+		// Best-effort: a reconnecting client misses this one update but
+		// the in-memory fan-out below still reaches anyone watching now.
+		fmt.Printf("media: recording job %s stage %s: %v\n", job.ID, p.Stage, err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, ch := range m.subs[job.VideoID] {
+		select {
+		case ch <- p:
+		default:
+			// Slow subscriber; drop the update rather than block the worker.
+		}
+	}
+}
+
+func (m *Manager) closeSubs(videoID uuid.UUID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, ch := range m.subs[videoID] {
+		close(ch)
+	}
+	delete(m.subs, videoID)
+}
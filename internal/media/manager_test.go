@@ -0,0 +1,129 @@
+package media
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bootdotdev/learn-file-storage-s3-golang-starter/internal/database"
+	"github.com/google/uuid"
+)
+
+// fakeStore is a minimal in-memory Store used to exercise Manager's
+// pubsub/locking behavior without a real database backend.
+type fakeStore struct {
+	mu   sync.Mutex
+	jobs map[uuid.UUID]database.VideoJob
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{jobs: make(map[uuid.UUID]database.VideoJob)}
+}
+
+func (s *fakeStore) GetVideo(id uuid.UUID) (database.Video, error) {
+	return database.Video{}, sql.ErrNoRows
+}
+
+func (s *fakeStore) UpdateVideo(video database.Video) error {
+	return nil
+}
+
+func (s *fakeStore) CreateVideoJob(jobID, videoID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[jobID] = database.VideoJob{ID: jobID, VideoID: videoID, Stage: "uploaded", UpdatedAt: time.Now()}
+	return nil
+}
+
+func (s *fakeStore) UpdateVideoJobStage(jobID uuid.UUID, stage string, percent float64, errMsg string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job := s.jobs[jobID]
+	job.Stage = stage
+	job.Percent = percent
+	job.Error = errMsg
+	job.UpdatedAt = time.Now()
+	s.jobs[jobID] = job
+	return nil
+}
+
+func (s *fakeStore) GetLatestVideoJobForVideo(videoID uuid.UUID) (database.VideoJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var latest database.VideoJob
+	found := false
+	for _, job := range s.jobs {
+		if job.VideoID != videoID {
+			continue
+		}
+		if !found || job.UpdatedAt.After(latest.UpdatedAt) {
+			latest = job
+			found = true
+		}
+	}
+	if !found {
+		return database.VideoJob{}, sql.ErrNoRows
+	}
+	return latest, nil
+}
+
+// TestSubscribeWithLatest_RacesCloseSubs pits SubscribeWithLatest against
+// a concurrent closeSubs (as run by a job finishing) for the same
+// videoID. Before SubscribeWithLatest existed, a caller that read the
+// latest progress and then subscribed in two separate steps could land
+// its Subscribe call in the gap after closeSubs ran, recreating a
+// subscriber entry nothing would ever close. SubscribeWithLatest must
+// never leave a caller waiting on a channel nothing will close: it either
+// already observes the terminal state (so the caller needn't read the
+// channel), or it registers before closeSubs runs and gets closed along
+// with every other live subscriber.
+func TestSubscribeWithLatest_RacesCloseSubs(t *testing.T) {
+	store := newFakeStore()
+	m := &Manager{
+		store: store,
+		subs:  make(map[uuid.UUID][]chan Progress),
+	}
+
+	jobID := uuid.New()
+	videoID := uuid.New()
+	if err := store.CreateVideoJob(jobID, videoID); err != nil {
+		t.Fatalf("CreateVideoJob: %v", err)
+	}
+
+	for i := 0; i < 200; i++ {
+		var wg sync.WaitGroup
+		var ch <-chan Progress
+		var latest Progress
+		var ok bool
+
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			ch, latest, ok = m.SubscribeWithLatest(videoID)
+		}()
+		go func() {
+			defer wg.Done()
+			m.publish(Job{ID: jobID, VideoID: videoID}, Progress{Stage: StageDone, Percent: 100})
+			m.closeSubs(videoID)
+		}()
+		wg.Wait()
+
+		if ok && latest.Stage == StageDone {
+			// Observed the terminal state directly; the channel may never
+			// receive anything further, which is fine.
+			continue
+		}
+
+		select {
+		case _, stillOpen := <-ch:
+			if stillOpen {
+				// Drain until closed.
+				for range ch {
+				}
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("iteration %d: channel never closed after job finished", i)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package media
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Dimensions used for auto-generated thumbnails, matching the 16:9 crop
+// the frontend expects everywhere a thumbnail is displayed.
+const (
+	ThumbnailWidth  = 177
+	ThumbnailHeight = 100
+)
+
+type ffprobeOutput struct {
+	Streams []struct {
+		Width  int `json:"width"`
+		Height int `json:"height"`
+	} `json:"streams"`
+}
+
+// approx checks if two floats are close enough
+func approx(a, b float64) bool {
+	const tolerance = 0.05
+	return (a > b-tolerance) && (a < b+tolerance)
+}
+
+func getVideoAspectRatio(filePath string) (string, error) {
+	cmd := exec.Command("ffprobe",
+		"-v", "error",
+		"-print_format", "json",
+		"-show_streams", filePath)
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to run ffprobe: %w", err)
+	}
+
+	var parsed ffprobeOutput
+	if err := json.Unmarshal(out.Bytes(), &parsed); err != nil {
+		return "", fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+
+	if len(parsed.Streams) == 0 {
+		return "", errors.New("no streams found in video")
+	}
+
+	width := parsed.Streams[0].Width
+	height := parsed.Streams[0].Height
+
+	if width == 0 || height == 0 {
+		return "", errors.New("invalid width or height")
+	}
+
+	ratio := float64(width) / float64(height)
+
+	switch {
+	case approx(ratio, 16.0/9.0):
+		return "landscape", nil
+	case approx(ratio, 9.0/16.0):
+		return "portrait", nil
+	default:
+		return "other", nil
+	}
+}
+
+func processVideoForFastStart(filePath string) (string, error) {
+	outputPath := filePath + ".processing"
+
+	cmd := exec.Command(
+		"ffmpeg",
+		"-i", filePath,
+		"-c", "copy",
+		"-movflags", "faststart",
+		"-f", "mp4",
+		outputPath,
+	)
+
+	// Optional: print output for debugging
+	cmd.Stdout = nil
+	cmd.Stderr = nil
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to process video for fast start: %w", err)
+	}
+
+	return outputPath, nil
+}
+
+type ffprobeFormatOutput struct {
+	Format struct {
+		FormatName string `json:"format_name"`
+	} `json:"format"`
+	Streams []struct {
+		CodecType string `json:"codec_type"`
+		CodecName string `json:"codec_name"`
+	} `json:"streams"`
+}
+
+// ValidateMP4H264 confirms filePath's actual container and codec are mp4
+// and h264 via ffprobe, independent of whatever extension or
+// Content-Type the upload arrived with.
+func ValidateMP4H264(filePath string) error {
+	cmd := exec.Command("ffprobe",
+		"-v", "error",
+		"-print_format", "json",
+		"-show_format", "-show_streams", filePath)
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run ffprobe: %w", err)
+	}
+
+	var parsed ffprobeFormatOutput
+	if err := json.Unmarshal(out.Bytes(), &parsed); err != nil {
+		return fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+
+	if !strings.Contains(parsed.Format.FormatName, "mp4") {
+		return fmt.Errorf("container %q is not mp4", parsed.Format.FormatName)
+	}
+
+	for _, s := range parsed.Streams {
+		if s.CodecType == "video" && s.CodecName == "h264" {
+			return nil
+		}
+	}
+	return errors.New("no h264 video stream found")
+}
+
+// GetVideoDuration returns a video's duration in seconds via ffprobe.
+func GetVideoDuration(filePath string) (float64, error) {
+	cmd := exec.Command("ffprobe",
+		"-v", "error",
+		"-show_entries", "format=duration",
+		"-of", "default=noprint_wrappers=1:nokey=1",
+		filePath)
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+
+	if err := cmd.Run(); err != nil {
+		return 0, fmt.Errorf("failed to run ffprobe: %w", err)
+	}
+
+	duration, err := strconv.ParseFloat(strings.TrimSpace(out.String()), 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse video duration: %w", err)
+	}
+	return duration, nil
+}
+
+// GenerateThumbnailAt grabs a single frame from filePath at atSeconds and
+// scales it to ThumbnailWidth x ThumbnailHeight, returning the path to
+// the generated JPEG. Callers are responsible for removing it.
+func GenerateThumbnailAt(filePath string, atSeconds float64) (string, error) {
+	outputPath := filePath + ".thumb.jpg"
+
+	cmd := exec.Command(
+		"ffmpeg",
+		"-ss", strconv.FormatFloat(atSeconds, 'f', 2, 64),
+		"-i", filePath,
+		"-frames:v", "1",
+		"-vf", fmt.Sprintf("scale=%d:%d", ThumbnailWidth, ThumbnailHeight),
+		"-y",
+		outputPath,
+	)
+	cmd.Stdout = nil
+	cmd.Stderr = nil
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("failed to generate thumbnail: %w", err)
+	}
+	return outputPath, nil
+}
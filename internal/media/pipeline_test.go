@@ -0,0 +1,88 @@
+package media
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// newFixtureMP4 generates a tiny synthetic mp4/h264 file via ffmpeg's
+// lavfi test source, rather than committing a binary fixture to the
+// repo. It skips the test when ffmpeg/ffprobe aren't available, which is
+// the case in plenty of CI and local setups.
+func newFixtureMP4(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		t.Skip("ffmpeg not available")
+	}
+	if _, err := exec.LookPath("ffprobe"); err != nil {
+		t.Skip("ffprobe not available")
+	}
+
+	path := filepath.Join(t.TempDir(), "fixture.mp4")
+	cmd := exec.Command("ffmpeg",
+		"-f", "lavfi", "-i", "testsrc=duration=1:size=320x240:rate=10",
+		"-pix_fmt", "yuv420p",
+		"-c:v", "libx264",
+		"-y", path,
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Skipf("generating fixture mp4: %v\n%s", err, out)
+	}
+	return path
+}
+
+func TestGetVideoDuration(t *testing.T) {
+	path := newFixtureMP4(t)
+
+	duration, err := GetVideoDuration(path)
+	if err != nil {
+		t.Fatalf("GetVideoDuration: %v", err)
+	}
+	if duration <= 0 {
+		t.Errorf("duration = %v, want > 0", duration)
+	}
+}
+
+func TestGenerateThumbnailAt(t *testing.T) {
+	path := newFixtureMP4(t)
+
+	thumbnailPath, err := GenerateThumbnailAt(path, 0.1)
+	if err != nil {
+		t.Fatalf("GenerateThumbnailAt: %v", err)
+	}
+	defer os.Remove(thumbnailPath)
+
+	info, err := os.Stat(thumbnailPath)
+	if err != nil {
+		t.Fatalf("stat generated thumbnail: %v", err)
+	}
+	if info.Size() == 0 {
+		t.Error("generated thumbnail is empty")
+	}
+}
+
+func TestValidateMP4H264(t *testing.T) {
+	path := newFixtureMP4(t)
+
+	if err := ValidateMP4H264(path); err != nil {
+		t.Errorf("ValidateMP4H264(%s) = %v, want nil", path, err)
+	}
+}
+
+func TestValidateMP4H264_RejectsNonVideo(t *testing.T) {
+	if _, err := exec.LookPath("ffprobe"); err != nil {
+		t.Skip("ffprobe not available")
+	}
+
+	path := filepath.Join(t.TempDir(), "not-a-video.mp4")
+	if err := os.WriteFile(path, []byte("definitely not an mp4 container"), 0o644); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+
+	if err := ValidateMP4H264(path); err == nil {
+		t.Error("ValidateMP4H264 on non-video bytes = nil, want error")
+	}
+}
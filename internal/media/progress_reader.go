@@ -0,0 +1,34 @@
+package media
+
+import "io"
+
+// progressReader wraps an io.Reader and reports back, via onUpdate, what
+// percent of total bytes have been read so far. Updates are throttled to
+// whole percentage points (plus a final call at EOF) rather than firing
+// on every Read(), since each onUpdate here triggers a database write —
+// at a typical 32KB read size, an unthrottled callback means tens of
+// thousands of writes over a 1GB upload.
+type progressReader struct {
+	r            io.Reader
+	total        int64
+	read         int64
+	onUpdate     func(percent float64)
+	lastReported float64
+}
+
+func newProgressReader(r io.Reader, total int64, onUpdate func(percent float64)) *progressReader {
+	return &progressReader{r: r, total: total, onUpdate: onUpdate}
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.read += int64(n)
+	if p.total > 0 && p.onUpdate != nil {
+		percent := float64(p.read) / float64(p.total) * 100
+		if percent-p.lastReported >= 1 || err == io.EOF {
+			p.lastReported = percent
+			p.onUpdate(percent)
+		}
+	}
+	return n, err
+}